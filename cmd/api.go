@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"git.xx.network/elixxir/coupons/storage"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+	"net/http"
+	"sync/atomic"
+)
+
+// apiCmd starts the bot and an admin/metrics HTTP API alongside it, so
+// operators can check on the bot and issue coupons without DM'ing the zero
+// user.
+var apiCmd = &cobra.Command{
+	Use:               "api",
+	Short:             "Starts the coupon bot and an admin/metrics HTTP API.",
+	Long:              "Starts the coupon bot and an HTTP API exposing /healthz, /readyz, /metrics and /v1/coupons for operational use.",
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: requireAPI,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, s, _, err := startBot()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to start bot: %+v", err)
+		}
+		ready.Store(true)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/healthz", healthzHandler)
+		mux.HandleFunc("/readyz", readyzHandler)
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/v1/coupons", couponsHandler(s))
+
+		addr := fmt.Sprintf(":%d", GetConfig().API.Port)
+		jww.INFO.Printf("Admin API listening on %s", addr)
+		if err := http.ListenAndServe(addr, requireAPIKey(mux)); err != nil {
+			jww.FATAL.Panicf("Admin API server exited: %+v", err)
+		}
+	},
+}
+
+// ready reports whether the network follower has been started.
+var ready atomic.Value
+
+func init() {
+	ready.Store(false)
+	rootCmd.AddCommand(apiCmd)
+}
+
+// requireAPIKey protects every route other than /healthz and /readyz with a
+// constant-time comparison against the configured API key.
+func requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(GetConfig().API.Key)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if isReady, _ := ready.Load().(bool); isReady {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Error(w, "not ready", http.StatusServiceUnavailable)
+}
+
+// couponsHandler lists, issues and revokes coupons via the storage backend.
+func couponsHandler(s *storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			coupons, err := s.ListCoupons()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(coupons); err != nil {
+				jww.ERROR.Printf("Failed to encode coupon list: %+v", err)
+			}
+		case http.MethodPost:
+			code := r.URL.Query().Get("code")
+			if err := s.IssueCoupon(code); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			couponsIssuedTotal.Inc()
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			code := r.URL.Query().Get("code")
+			if err := s.RevokeCoupon(code); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
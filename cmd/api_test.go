@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func withAPIKey(t *testing.T, key string) {
+	old := GetConfig()
+	t.Cleanup(func() { setConfig(old) })
+
+	c := old
+	c.API.Key = key
+	setConfig(c)
+}
+
+func passthroughHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRequireAPIKey_AllowsHealthzWithoutKey(t *testing.T) {
+	withAPIKey(t, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	requireAPIKey(passthroughHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /healthz to bypass the API key check, got %d", rec.Code)
+	}
+}
+
+func TestRequireAPIKey_AllowsReadyzWithoutKey(t *testing.T) {
+	withAPIKey(t, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	requireAPIKey(passthroughHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /readyz to bypass the API key check, got %d", rec.Code)
+	}
+}
+
+func TestRequireAPIKey_RejectsMissingKey(t *testing.T) {
+	withAPIKey(t, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/coupons", nil)
+	rec := httptest.NewRecorder()
+	requireAPIKey(passthroughHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with no Authorization header, got %d", rec.Code)
+	}
+}
+
+func TestRequireAPIKey_RejectsWrongKey(t *testing.T) {
+	withAPIKey(t, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/coupons", nil)
+	req.Header.Set("Authorization", "wrong")
+	rec := httptest.NewRecorder()
+	requireAPIKey(passthroughHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with a wrong key, got %d", rec.Code)
+	}
+}
+
+func TestRequireAPIKey_AllowsCorrectKey(t *testing.T) {
+	withAPIKey(t, "secret")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/coupons", nil)
+	req.Header.Set("Authorization", "secret")
+	rec := httptest.NewRecorder()
+	requireAPIKey(passthroughHandler()).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the correct key, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"git.xx.network/elixxir/coupons/coupons"
+	"git.xx.network/elixxir/coupons/storage"
+	"github.com/skip2/go-qrcode"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/api"
+	"gitlab.com/elixxir/client/interfaces/message"
+	"gitlab.com/elixxir/crypto/contact"
+	"gitlab.com/xx_network/primitives/id"
+	"gitlab.com/xx_network/primitives/utils"
+	"time"
+)
+
+// startBot brings up storage and the client session, writes the registration
+// QR code, starts the network follower and registers the coupon listener on
+// the zero user. It is shared by every subcommand that runs the bot itself.
+func startBot() (*api.Client, *storage.Storage, *coupons.Impl, error) {
+	s, err := loadStorage()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cl, err := loadClient()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c := GetConfig()
+
+	// Generate QR code
+	qrLevel := qrcode.RecoveryLevel(c.QR.Level)
+	qr, err := cl.GetUser().GetContact().MakeQR(c.QR.Size, qrLevel)
+	if err != nil {
+		jww.FATAL.Panicf("Failed to generate QR code: %+v", err)
+	}
+	// Save the QR code PNG to file
+	err = utils.WriteFile(c.QR.Path, qr, utils.FilePerms, utils.DirPerms)
+	if err != nil {
+		jww.FATAL.Panicf("Failed to write QR code: %+v", err)
+	}
+
+	// Start network follower
+	networkFollowerTimeout := time.Duration(c.Network.FollowerTimeout) * time.Second
+	err = cl.StartNetworkFollower(networkFollowerTimeout)
+	if err != nil {
+		jww.FATAL.Panicf("Failed to start network follower: %+v", err)
+	}
+
+	// Create & register callback to confirm any authenticated channel requests
+	rcb := func(requestor contact.Contact, message string) {
+		rid, err := cl.ConfirmAuthenticatedChannel(requestor)
+		if err != nil {
+			listenerErrorsTotal.Inc()
+			jww.ERROR.Printf("Failed to confirm authentecated channel to %+v: %+v", requestor, err)
+			return
+		}
+		authConfirmsTotal.Inc()
+		jww.DEBUG.Printf("Authenticated channel to %+v created over round %d", requestor, rid)
+	}
+	cl.GetAuthRegistrar().AddGeneralRequestCallback(rcb)
+
+	// Create coupons impl & register listener on zero user for text messages.
+	impl := coupons.New(s, cl)
+	cl.GetSwitchboard().RegisterListener(&id.ZeroUser, message.Text, impl)
+
+	return cl, s, impl, nil
+}
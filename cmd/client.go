@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/elixxir/client/api"
+	"gitlab.com/elixxir/client/interfaces/params"
+	"io/ioutil"
+	"os"
+)
+
+// legacySessionPaths are checked for an existing session when none is found
+// at the configured session path, so upgrading operators aren't forced to
+// recreate a session just because its default location changed.
+var legacySessionPaths = []string{
+	"./coupon-bot-session",
+	"/var/lib/coupon-bot/session",
+	"/etc/coupon-bot/session",
+}
+
+// relocateSession moves an existing session from one of legacySessionPaths
+// to target, if one is found and target doesn't already exist.
+func relocateSession(target string) error {
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+
+	for _, legacy := range legacySessionPaths {
+		if legacy == target {
+			continue
+		}
+		if _, err := os.Stat(legacy); err != nil {
+			continue
+		}
+
+		jww.INFO.Printf("Found existing session at %s, relocating to %s", legacy, target)
+		return os.Rename(legacy, target)
+	}
+
+	return nil
+}
+
+// loadClient creates a new client session if one does not already exist at
+// the configured session path, then logs in and returns the client object.
+// It is shared by every subcommand that needs network access.
+func loadClient() (*api.Client, error) {
+	session := GetConfig().Session
+
+	if err := relocateSession(session.Path); err != nil {
+		jww.ERROR.Printf("Failed to relocate existing session to %s: %+v", session.Path, err)
+	}
+
+	if _, err := os.Stat(session.Path); os.IsNotExist(err) {
+		ndfJSON, err := ioutil.ReadFile(GetConfig().Network.NDF)
+		if err != nil {
+			jww.FATAL.Panicf("Failed to read NDF: %+v", err)
+		}
+		err = api.NewClient(string(ndfJSON), session.Path, []byte(session.Pass), "")
+		if err != nil {
+			jww.FATAL.Panicf("Failed to create new client: %+v", err)
+		}
+	}
+
+	cl, err := api.Login(session.Path, []byte(session.Pass), params.GetDefaultNetwork())
+	if err != nil {
+		jww.FATAL.Panicf("Failed to initialize client: %+v", err)
+	}
+	return cl, nil
+}
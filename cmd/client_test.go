@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRelocateSession_MovesLegacySessionToTarget(t *testing.T) {
+	old := legacySessionPaths
+	t.Cleanup(func() { legacySessionPaths = old })
+
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "legacy-session")
+	target := filepath.Join(dir, "session")
+	if err := os.Mkdir(legacy, 0o700); err != nil {
+		t.Fatalf("failed to create legacy session dir: %+v", err)
+	}
+
+	legacySessionPaths = []string{legacy}
+
+	if err := relocateSession(target); err != nil {
+		t.Fatalf("relocateSession failed: %+v", err)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Fatalf("expected session at %s, got: %+v", target, err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("expected legacy session to be gone, got: %+v", err)
+	}
+}
+
+func TestRelocateSession_NoOpWhenTargetAlreadyExists(t *testing.T) {
+	old := legacySessionPaths
+	t.Cleanup(func() { legacySessionPaths = old })
+
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "legacy-session")
+	target := filepath.Join(dir, "session")
+	if err := os.Mkdir(legacy, 0o700); err != nil {
+		t.Fatalf("failed to create legacy session dir: %+v", err)
+	}
+	if err := os.Mkdir(target, 0o700); err != nil {
+		t.Fatalf("failed to create target session dir: %+v", err)
+	}
+
+	legacySessionPaths = []string{legacy}
+
+	if err := relocateSession(target); err != nil {
+		t.Fatalf("relocateSession failed: %+v", err)
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		t.Fatalf("expected legacy session to be left alone, got: %+v", err)
+	}
+}
+
+func TestRelocateSession_NoOpWhenNoLegacySessionFound(t *testing.T) {
+	old := legacySessionPaths
+	t.Cleanup(func() { legacySessionPaths = old })
+
+	dir := t.TempDir()
+	legacySessionPaths = []string{filepath.Join(dir, "does-not-exist")}
+
+	target := filepath.Join(dir, "session")
+	if err := relocateSession(target); err != nil {
+		t.Fatalf("relocateSession failed: %+v", err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected no session to be created, got: %+v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/skip2/go-qrcode"
+	jww "github.com/spf13/jwalterweatherman"
+	"github.com/spf13/viper"
+	"net"
+	"strings"
+	"sync"
+)
+
+// cfgMu guards cfg so in-flight work always sees a consistent snapshot of
+// the configuration, even while a hot reload is in progress.
+var (
+	cfgMu sync.RWMutex
+	cfg   Config
+)
+
+// GetConfig returns a snapshot of the current configuration. Long-running
+// code in this package (the API server) calls this instead of holding onto
+// the Config it received at startup, so it picks up hot-reloaded values. The
+// coupon listener (coupons.Impl) is not given this accessor and does not see
+// reloaded values.
+func GetConfig() Config {
+	cfgMu.RLock()
+	defer cfgMu.RUnlock()
+	return cfg
+}
+
+// setConfig atomically replaces the current configuration.
+func setConfig(newCfg Config) {
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	cfg = newCfg
+}
+
+// Config is the typed form of every key exposed via flags/viper. Subcommands
+// should read from this rather than calling viper.Get* directly.
+type Config struct {
+	DB      DBConfig      `mapstructure:",squash"`
+	Session SessionConfig `mapstructure:",squash"`
+	QR      QRConfig      `mapstructure:",squash"`
+	Log     LogConfig     `mapstructure:",squash"`
+	Network NetworkConfig `mapstructure:",squash"`
+	API     APIConfig     `mapstructure:",squash"`
+}
+
+// DBConfig holds the coupon storage connection parameters.
+type DBConfig struct {
+	Address  string `mapstructure:"dbAddress"`
+	Username string `mapstructure:"dbUsername"`
+	Password string `mapstructure:"dbPassword"`
+	Name     string `mapstructure:"dbName"`
+}
+
+// SessionConfig holds the xx network client session parameters.
+type SessionConfig struct {
+	Path string `mapstructure:"sessionPath"`
+	Pass string `mapstructure:"sessionPass"`
+}
+
+// QRConfig holds the parameters used to render the registration QR code.
+type QRConfig struct {
+	Path  string `mapstructure:"qrPath"`
+	Size  int    `mapstructure:"qrSize"`
+	Level int    `mapstructure:"qrLevel"`
+}
+
+// LogConfig holds the logging parameters.
+type LogConfig struct {
+	Level       int    `mapstructure:"logLevel"`
+	LevelName   string `mapstructure:"logLevelName"`
+	Path        string `mapstructure:"log"`
+	MaxSize     int    `mapstructure:"logMaxSize"`
+	MaxBackups  int    `mapstructure:"logMaxBackups"`
+	MaxAge      int    `mapstructure:"logMaxAge"`
+	Compress    bool   `mapstructure:"logCompress"`
+	GRPCVerbose bool   `mapstructure:"logGRPCVerbose"`
+	// PackageLevels maps a package name (e.g. "coupons", "storage") to the
+	// minimum level it should log at, overriding LevelName/Level for log
+	// lines tagged with that package. Config-file only; not bound to a flag.
+	PackageLevels map[string]string `mapstructure:"logLevels"`
+}
+
+// NetworkConfig holds the parameters used to bring up the cMix client.
+type NetworkConfig struct {
+	NDF             string `mapstructure:"ndf"`
+	FollowerTimeout int    `mapstructure:"networkFollowerTimeout"`
+}
+
+// APIConfig holds the parameters for the admin/metrics HTTP API.
+type APIConfig struct {
+	Port int    `mapstructure:"apiPort"`
+	Key  string `mapstructure:"apiKey"`
+}
+
+// loadConfig unmarshals viper's view of the config into cfg. It does not
+// validate it: which settings actually matter depends on the subcommand
+// being run (issue/redeem never touch the client, version touches nothing at
+// all), so validation is done by each subcommand's PersistentPreRunE instead
+// — see requireDB/requireClientAndQR/requireBot in require.go. It also registers a
+// viper.WatchConfig handler so later edits to the config file are picked up
+// without a restart.
+func loadConfig() {
+	var newCfg Config
+	if err := viper.Unmarshal(&newCfg); err != nil {
+		jww.FATAL.Panicf("Failed to unmarshal config: %+v", err)
+	}
+
+	setConfig(newCfg)
+
+	watchConfigOnce.Do(func() {
+		viper.OnConfigChange(func(_ fsnotify.Event) { reloadConfig() })
+		viper.WatchConfig()
+	})
+}
+
+// watchConfigOnce ensures viper.WatchConfig is only registered once, even
+// though loadConfig runs on every cobra.OnInitialize pass.
+var watchConfigOnce sync.Once
+
+// restartRequiredFields are the Config fields that can't be changed on a
+// running process; edits to them are logged but not applied.
+type restartRequiredFields struct {
+	DB      DBConfig
+	Session SessionConfig
+	NDF     string
+}
+
+// reloadConfig re-unmarshals viper's view of the config, applies whatever
+// can be safely changed live, and logs anything that still needs a restart.
+func reloadConfig() {
+	var newCfg Config
+	if err := viper.Unmarshal(&newCfg); err != nil {
+		log.Errorf("Failed to unmarshal reloaded config: %+v", err)
+		return
+	}
+
+	// ValidateBot, not Validate: serve runs with no apiKey configured at all,
+	// and a reload shouldn't start rejecting its otherwise-valid config just
+	// because the admin API's settings don't apply to it.
+	if err := newCfg.ValidateBot(); err != nil {
+		log.Errorf("Reloaded config is invalid, keeping previous config: %+v", err)
+		return
+	}
+
+	old := GetConfig()
+	before := restartRequiredFields{old.DB, old.Session, old.Network.NDF}
+	after := restartRequiredFields{newCfg.DB, newCfg.Session, newCfg.Network.NDF}
+
+	var needsRestart []string
+	if before.DB != after.DB {
+		needsRestart = append(needsRestart, "database connection")
+		newCfg.DB = old.DB
+	}
+	if before.Session != after.Session {
+		needsRestart = append(needsRestart, "session path/password")
+		newCfg.Session = old.Session
+	}
+	if before.NDF != after.NDF {
+		needsRestart = append(needsRestart, "ndf")
+		newCfg.Network.NDF = old.Network.NDF
+	}
+
+	setConfig(newCfg)
+	initLog()
+
+	if len(needsRestart) > 0 {
+		log.Warnf("Config reloaded, but %s changed and requires a restart to take effect", strings.Join(needsRestart, ", "))
+	} else {
+		log.Info("Config reloaded")
+	}
+}
+
+// Validate checks the DB, session, QR, network and admin API settings of c,
+// and returns a single error aggregating every problem found. Like
+// ValidateSession, it does not check that the NDF file exists. Subcommands that
+// don't touch every part of the config (e.g. issue/redeem, which never
+// create a client) should prefer the narrower ValidateX methods below so
+// they aren't rejected over settings they don't use.
+func (c Config) Validate() error {
+	var problems []string
+	problems = append(problems, c.botProblems()...)
+	problems = append(problems, c.apiProblems()...)
+	return problemsErr(problems)
+}
+
+// ValidateBot checks the settings startBot touches, but not the admin API
+// settings: serve runs the bot without an API server, so it shouldn't be
+// rejected over a missing apiKey.
+func (c Config) ValidateBot() error { return problemsErr(c.botProblems()) }
+
+func (c Config) botProblems() []string {
+	var problems []string
+	problems = append(problems, c.dbProblems()...)
+	problems = append(problems, c.sessionProblems()...)
+	problems = append(problems, c.qrProblems()...)
+	problems = append(problems, c.networkProblems()...)
+	return problems
+}
+
+// ValidateDB checks the settings used by loadStorage.
+func (c Config) ValidateDB() error { return problemsErr(c.dbProblems()) }
+
+// ValidateSession checks the settings used by loadClient. It does not check
+// that the NDF exists: the NDF is only read when bootstrapping a brand new
+// session, and loadClient surfaces that failure on its own when it happens.
+func (c Config) ValidateSession() error { return problemsErr(c.sessionProblems()) }
+
+// ValidateQR checks the settings used to render the registration QR code.
+func (c Config) ValidateQR() error { return problemsErr(c.qrProblems()) }
+
+// ValidateNetwork checks the settings used to start the network follower.
+func (c Config) ValidateNetwork() error { return problemsErr(c.networkProblems()) }
+
+// ValidateAPI checks the settings used by the admin/metrics HTTP API.
+func (c Config) ValidateAPI() error { return problemsErr(c.apiProblems()) }
+
+func (c Config) dbProblems() []string {
+	var problems []string
+	if c.DB.Address != "" {
+		if _, _, err := net.SplitHostPort(c.DB.Address); err != nil {
+			problems = append(problems, fmt.Sprintf("dbAddress %q is invalid: %+v", c.DB.Address, err))
+		}
+	}
+	return problems
+}
+
+func (c Config) sessionProblems() []string {
+	var problems []string
+	if c.Session.Pass == "" {
+		problems = append(problems, "sessionPass must not be empty")
+	}
+	return problems
+}
+
+func (c Config) qrProblems() []string {
+	var problems []string
+	if c.QR.Size < 128 || c.QR.Size > 2048 {
+		problems = append(problems, fmt.Sprintf("qrSize %d must be between 128 and 2048", c.QR.Size))
+	}
+	if c.QR.Level < int(qrcode.Low) || c.QR.Level > int(qrcode.Highest) {
+		problems = append(problems, fmt.Sprintf("qrLevel %d is not a valid recovery level", c.QR.Level))
+	}
+	return problems
+}
+
+func (c Config) networkProblems() []string {
+	var problems []string
+	if c.Network.FollowerTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("networkFollowerTimeout %d must be greater than 0", c.Network.FollowerTimeout))
+	}
+	return problems
+}
+
+func (c Config) apiProblems() []string {
+	var problems []string
+	if c.API.Key == "" {
+		problems = append(problems, "apiKey must be set to start the admin API")
+	}
+	return problems
+}
+
+// problemsErr turns a list of problem descriptions into a single error, or
+// nil if there were none.
+func problemsErr(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d config problem(s):\n\t%s", len(problems), strings.Join(problems, "\n\t"))
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"path/filepath"
+	"testing"
+)
+
+// setViperForTest sets every key reloadConfig reads via viper.Unmarshal to a
+// valid, known value so tests aren't affected by whatever config file (if
+// any) happens to be on the machine running them. The values mirror
+// validConfigForTest so priming cfg with that struct leaves no diff for
+// reloadConfig's restart-required check to trip on.
+func setViperForTest() {
+	viper.Set("dbAddress", "localhost:5432")
+	viper.Set("dbUsername", "")
+	viper.Set("dbPassword", "")
+	viper.Set("dbName", "")
+	viper.Set("sessionPath", "session")
+	viper.Set("sessionPass", "hunter2")
+	viper.Set("ndf", "ndf.json")
+	viper.Set("qrPath", "qr.png")
+	viper.Set("qrSize", 256)
+	viper.Set("qrLevel", 0)
+	viper.Set("networkFollowerTimeout", 30)
+	viper.Set("logLevel", 0)
+	viper.Set("logLevelName", "")
+	viper.Set("log", "coupon-bot.log")
+	viper.Set("logMaxSize", 100)
+	viper.Set("logMaxBackups", 3)
+	viper.Set("logMaxAge", 28)
+	viper.Set("logCompress", true)
+	viper.Set("logGRPCVerbose", false)
+	viper.Set("apiPort", 8080)
+	viper.Set("apiKey", "")
+}
+
+// primeReloadTest points viper and cfg at the same known-good config, so the
+// next reloadConfig call starts from a stable baseline instead of tripping
+// the restart-required diff against whatever was there before.
+func primeReloadTest(t *testing.T) {
+	oldCfg := GetConfig()
+	t.Cleanup(func() { setConfig(oldCfg) })
+
+	setViperForTest()
+	viper.Set("log", filepath.Join(t.TempDir(), "test.log"))
+	setConfig(validConfigForTest())
+}
+
+func TestReloadConfig_AppliesLiveSafeChange(t *testing.T) {
+	primeReloadTest(t)
+
+	viper.Set("qrSize", 512)
+	reloadConfig()
+
+	if got := GetConfig().QR.Size; got != 512 {
+		t.Fatalf("expected qrSize 512 to be applied, got %d", got)
+	}
+}
+
+func TestReloadConfig_RevertsRestartRequiredFields(t *testing.T) {
+	primeReloadTest(t)
+	baseline := GetConfig()
+
+	// Change a restart-required field behind reloadConfig's back.
+	viper.Set("dbAddress", "otherhost:5432")
+	reloadConfig()
+
+	got := GetConfig()
+	if got.DB.Address != baseline.DB.Address {
+		t.Fatalf("expected dbAddress change to be reverted pending restart, got %q", got.DB.Address)
+	}
+}
+
+func TestReloadConfig_AppliesNonRestartFieldsEvenWhenRestartFieldChanged(t *testing.T) {
+	primeReloadTest(t)
+
+	viper.Set("dbAddress", "otherhost:5432")
+	viper.Set("qrSize", 512)
+	reloadConfig()
+
+	if got := GetConfig().QR.Size; got != 512 {
+		t.Fatalf("expected qrSize to still apply despite the pending DB change, got %d", got)
+	}
+}
+
+func TestReloadConfig_KeepsPreviousConfigWhenReloadedConfigIsInvalid(t *testing.T) {
+	primeReloadTest(t)
+	baseline := GetConfig()
+
+	viper.Set("sessionPass", "") // invalid: sessionPass must not be empty
+	reloadConfig()
+
+	if got := GetConfig(); got.Session != baseline.Session {
+		t.Fatalf("expected invalid reload to be rejected, session changed to %+v", got.Session)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func validConfigForTest() Config {
+	return Config{
+		DB:      DBConfig{Address: "localhost:5432"},
+		Session: SessionConfig{Path: "session", Pass: "hunter2"},
+		QR:      QRConfig{Path: "qr.png", Size: 256, Level: 0},
+		Network: NetworkConfig{NDF: "ndf.json", FollowerTimeout: 30},
+		API:     APIConfig{Port: 8080, Key: "hunter2"},
+	}
+}
+
+func TestConfigValidate_Valid(t *testing.T) {
+	if err := validConfigForTest().Validate(); err != nil {
+		t.Fatalf("expected valid config to pass, got: %+v", err)
+	}
+}
+
+func TestConfigValidate_AggregatesEveryProblem(t *testing.T) {
+	c := validConfigForTest()
+	c.DB.Address = "not-a-host-port"
+	c.Session.Pass = ""
+	c.QR.Size = 1
+	c.Network.FollowerTimeout = 0
+	c.API.Key = ""
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, want := range []string{"dbAddress", "sessionPass", "qrSize", "networkFollowerTimeout", "apiKey"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got: %s", want, err.Error())
+		}
+	}
+}
+
+func TestValidateBot_IgnoresAPIProblems(t *testing.T) {
+	c := validConfigForTest()
+	c.API.Key = "" // irrelevant to ValidateBot
+
+	if err := c.ValidateBot(); err != nil {
+		t.Fatalf("ValidateBot should ignore apiKey, got: %+v", err)
+	}
+}
+
+func TestValidateAPI_ScopedToAPIFields(t *testing.T) {
+	c := validConfigForTest()
+	c.Session.Pass = "" // irrelevant to ValidateAPI
+
+	if err := c.ValidateAPI(); err != nil {
+		t.Fatalf("ValidateAPI should ignore session problems, got: %+v", err)
+	}
+
+	c.API.Key = ""
+	if err := c.ValidateAPI(); err == nil {
+		t.Fatal("expected an error for empty apiKey")
+	}
+}
+
+func TestConfigValidate_DoesNotRequireNDFToExist(t *testing.T) {
+	c := validConfigForTest()
+	c.Network.NDF = "/no/such/file.json"
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate should not check NDF existence, got: %+v", err)
+	}
+}
+
+func TestValidateSession_ScopedToSessionFields(t *testing.T) {
+	c := validConfigForTest()
+	c.DB.Address = "not-a-host-port" // irrelevant to ValidateSession
+
+	if err := c.ValidateSession(); err != nil {
+		t.Fatalf("ValidateSession should ignore DB problems, got: %+v", err)
+	}
+
+	c.Session.Pass = ""
+	if err := c.ValidateSession(); err == nil {
+		t.Fatal("expected an error for empty sessionPass")
+	}
+}
+
+func TestValidateQR_ScopedToQRFields(t *testing.T) {
+	c := validConfigForTest()
+	c.Session.Pass = "" // irrelevant to ValidateQR
+
+	if err := c.ValidateQR(); err != nil {
+		t.Fatalf("ValidateQR should ignore session problems, got: %+v", err)
+	}
+
+	c.QR.Size = 1
+	if err := c.ValidateQR(); err == nil {
+		t.Fatal("expected an error for an out-of-range qrSize")
+	}
+}
+
+func TestValidateDB_ScopedToDBFields(t *testing.T) {
+	c := validConfigForTest()
+	c.Session.Pass = "" // irrelevant to ValidateDB
+
+	if err := c.ValidateDB(); err != nil {
+		t.Fatalf("ValidateDB should ignore session problems, got: %+v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// issueCmd creates a new coupon in storage without requiring the bot to be
+// listening, so operators can pre-generate codes out of band.
+var issueCmd = &cobra.Command{
+	Use:               "issue <code>",
+	Short:             "Issues a new coupon code.",
+	Long:              "Writes a new, unredeemed coupon code to storage.",
+	Args:              cobra.ExactArgs(1),
+	PersistentPreRunE: requireDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := loadStorage()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to initialize storage interface: %+v", err)
+		}
+
+		code := args[0]
+		if err := s.IssueCoupon(code); err != nil {
+			jww.FATAL.Panicf("Failed to issue coupon %q: %+v", code, err)
+		}
+		couponsIssuedTotal.Inc()
+
+		jww.INFO.Printf("Issued coupon %q", code)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(issueCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	jww "github.com/spf13/jwalterweatherman"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"os"
+)
+
+// log is the structured logger used by the cmd package itself. Downstream
+// xx-network libraries still log through jww, which is shimmed in initLog to
+// share this same rotating output.
+var log = logrus.New()
+
+// packageLevels holds the parsed per-package overrides from the configured
+// logLevels, consulted by PackageLogger.
+var packageLevels = map[string]logrus.Level{}
+
+// PackageLogger returns a logger tagged with "package": name, logging at the
+// level configured for that package in logLevels, or at log's level if none
+// was configured. It's exported because the coupons/storage packages can't
+// import cmd to reach an unexported logger. Their constructors take neither
+// it nor GetConfig, so their own log lines are not tagged or filtered by the
+// per-package levels.
+func PackageLogger(name string) *logrus.Entry {
+	level, ok := packageLevels[name]
+	if !ok {
+		return log.WithField("package", name)
+	}
+
+	// Build a fresh *logrus.Logger rather than copying *log by value: Logger
+	// embeds a mutex, and copying a locked value trips go vet's copylocks
+	// check. Sharing Out/Formatter/Hooks keeps output consistent; only Level
+	// differs.
+	scoped := &logrus.Logger{
+		Out:       log.Out,
+		Formatter: log.Formatter,
+		Hooks:     log.Hooks,
+		Level:     level,
+	}
+	return scoped.WithField("package", name)
+}
+
+// initLog initializes structured JSON logging with rotation, applies
+// per-package level overrides, and shims jww so downstream xx-network
+// libraries keep logging through the same rotating output.
+func initLog() {
+	logCfg := GetConfig().Log
+
+	rotator := &lumberjack.Logger{
+		Filename:   logCfg.Path,
+		MaxSize:    logCfg.MaxSize,
+		MaxBackups: logCfg.MaxBackups,
+		MaxAge:     logCfg.MaxAge,
+		Compress:   logCfg.Compress,
+	}
+
+	log.SetOutput(rotator)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.SetLevel(parseLevel(logCfg.LevelName, logCfg.Level))
+
+	packageLevels = map[string]logrus.Level{}
+	for pkg, name := range logCfg.PackageLevels {
+		level, err := logrus.ParseLevel(name)
+		if err != nil {
+			log.Errorf("Invalid log level %q for package %q: %+v", name, pkg, err)
+			continue
+		}
+		packageLevels[pkg] = level
+	}
+
+	// Shim jww onto the same rotating output so downstream xx-network
+	// libraries, which log through jww directly, still end up in one place.
+	jww.SetLogOutput(rotator)
+	switch log.GetLevel() {
+	case logrus.TraceLevel:
+		jww.SetLogThreshold(jww.LevelTrace)
+		jww.SetStdoutThreshold(jww.LevelTrace)
+	case logrus.DebugLevel:
+		jww.SetLogThreshold(jww.LevelDebug)
+		jww.SetStdoutThreshold(jww.LevelDebug)
+	default:
+		jww.SetLogThreshold(jww.LevelInfo)
+		jww.SetStdoutThreshold(jww.LevelInfo)
+	}
+
+	// Only force verbose gRPC logging when explicitly requested; it used to
+	// be tied to logLevel>1, which drowned out everything else at trace.
+	if logCfg.GRPCVerbose {
+		if err := os.Setenv("GRPC_GO_LOG_SEVERITY_LEVEL", "info"); err != nil {
+			log.Errorf("Could not set GRPC_GO_LOG_SEVERITY_LEVEL: %+v", err)
+		}
+		if err := os.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "99"); err != nil {
+			log.Errorf("Could not set GRPC_GO_LOG_VERBOSITY_LEVEL: %+v", err)
+		}
+	}
+}
+
+// parseLevel resolves the effective log level, preferring name (logLevelName)
+// over the legacy numeric level (logLevel) when both are set.
+func parseLevel(name string, numeric int) logrus.Level {
+	if name != "" {
+		if level, err := logrus.ParseLevel(name); err == nil {
+			return level
+		}
+	}
+
+	switch {
+	case numeric > 1:
+		return logrus.TraceLevel
+	case numeric == 1:
+		return logrus.DebugLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
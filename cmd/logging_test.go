@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"testing"
+)
+
+func TestParseLevel_NamePreferredOverNumeric(t *testing.T) {
+	if got := parseLevel("warn", 2); got != logrus.WarnLevel {
+		t.Fatalf("expected logLevelName to win, got %v", got)
+	}
+}
+
+func TestParseLevel_InvalidNameFallsBackToNumeric(t *testing.T) {
+	if got := parseLevel("not-a-level", 1); got != logrus.DebugLevel {
+		t.Fatalf("expected fallback to numeric level, got %v", got)
+	}
+}
+
+func TestParseLevel_Numeric(t *testing.T) {
+	cases := []struct {
+		numeric int
+		want    logrus.Level
+	}{
+		{0, logrus.InfoLevel},
+		{1, logrus.DebugLevel},
+		{2, logrus.TraceLevel},
+	}
+	for _, c := range cases {
+		if got := parseLevel("", c.numeric); got != c.want {
+			t.Errorf("parseLevel(\"\", %d) = %v, want %v", c.numeric, got, c.want)
+		}
+	}
+}
+
+func TestPackageLogger_UsesOverrideLevel(t *testing.T) {
+	old := packageLevels
+	defer func() { packageLevels = old }()
+
+	packageLevels = map[string]logrus.Level{"coupons": logrus.DebugLevel}
+
+	entry := PackageLogger("coupons")
+	if entry.Logger.GetLevel() != logrus.DebugLevel {
+		t.Fatalf("expected overridden level %v, got %v", logrus.DebugLevel, entry.Logger.GetLevel())
+	}
+}
+
+func TestPackageLogger_FallsBackToDefaultLevel(t *testing.T) {
+	old := packageLevels
+	defer func() { packageLevels = old }()
+
+	packageLevels = map[string]logrus.Level{}
+	log.SetLevel(logrus.InfoLevel)
+
+	entry := PackageLogger("storage")
+	if entry.Logger != log {
+		t.Fatal("expected the shared logger when no override is configured")
+	}
+}
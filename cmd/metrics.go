@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus counters exposed on the /metrics route of the admin API.
+var (
+	couponsIssuedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coupon_bot_coupons_issued_total",
+		Help: "Total number of coupons issued.",
+	})
+	couponsRedeemedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coupon_bot_coupons_redeemed_total",
+		Help: "Total number of coupons redeemed by the redeem command; redemptions by the coupon listener are not counted.",
+	})
+	authConfirmsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coupon_bot_auth_confirms_total",
+		Help: "Total number of authenticated channel requests confirmed.",
+	})
+	listenerErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "coupon_bot_listener_errors_total",
+		Help: "Total number of failed authenticated channel confirmations; errors inside the coupon listener are not counted.",
+	})
+)
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+	"gitlab.com/xx_network/primitives/utils"
+)
+
+// qrCmd (re)generates the registration QR code for the bot's session
+// without starting the network follower.
+var qrCmd = &cobra.Command{
+	Use:               "qr",
+	Short:             "Generates the registration QR code for the bot's contact.",
+	Long:              "Logs in to the bot's existing session and writes its contact QR code to qrPath without starting the network follower.",
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: requireClientAndQR,
+	Run: func(cmd *cobra.Command, args []string) {
+		cl, err := loadClient()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to initialize client: %+v", err)
+		}
+
+		qr := GetConfig().QR
+		qrLevel := qrcode.RecoveryLevel(qr.Level)
+		qrBytes, err := cl.GetUser().GetContact().MakeQR(qr.Size, qrLevel)
+		if err != nil {
+			jww.FATAL.Panicf("Failed to generate QR code: %+v", err)
+		}
+
+		err = utils.WriteFile(qr.Path, qrBytes, utils.FilePerms, utils.DirPerms)
+		if err != nil {
+			jww.FATAL.Panicf("Failed to write QR code: %+v", err)
+		}
+
+		jww.INFO.Printf("Wrote QR code to %s", qr.Path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(qrCmd)
+}
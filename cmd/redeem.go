@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// redeemCmd marks a coupon code as redeemed without requiring the bot to be
+// listening, so operators can redeem codes reported through other channels.
+var redeemCmd = &cobra.Command{
+	Use:               "redeem <code>",
+	Short:             "Redeems a coupon code.",
+	Long:              "Marks a coupon code as redeemed in storage.",
+	Args:              cobra.ExactArgs(1),
+	PersistentPreRunE: requireDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := loadStorage()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to initialize storage interface: %+v", err)
+		}
+
+		code := args[0]
+		if err := s.RedeemCoupon(code); err != nil {
+			jww.FATAL.Panicf("Failed to redeem coupon %q: %+v", code, err)
+		}
+		couponsRedeemedTotal.Inc()
+
+		jww.INFO.Printf("Redeemed coupon %q", code)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(redeemCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// requireDB is a PersistentPreRunE for subcommands that only need storage
+// (issue, redeem): it validates just the DB settings, so an unrelated
+// problem elsewhere in the config (e.g. a missing sessionPass) doesn't block
+// them.
+func requireDB(cmd *cobra.Command, args []string) error {
+	return GetConfig().ValidateDB()
+}
+
+// requireClientAndQR is a PersistentPreRunE for subcommands that log in to
+// the client session and render a QR code from it (qr): it catches an
+// out-of-range qrSize/qrLevel before MakeQR sees it.
+func requireClientAndQR(cmd *cobra.Command, args []string) error {
+	c := GetConfig()
+	if err := c.ValidateSession(); err != nil {
+		return err
+	}
+	return c.ValidateQR()
+}
+
+// requireClientAndDB is a PersistentPreRunE for subcommands that need both
+// the client session and storage (status).
+func requireClientAndDB(cmd *cobra.Command, args []string) error {
+	c := GetConfig()
+	if err := c.ValidateSession(); err != nil {
+		return err
+	}
+	return c.ValidateDB()
+}
+
+// requireBot is a PersistentPreRunE for subcommands that run the bot itself
+// but not the admin API (serve): it validates everything startBot touches.
+func requireBot(cmd *cobra.Command, args []string) error {
+	return GetConfig().ValidateBot()
+}
+
+// requireAPI is a PersistentPreRunE for the subcommand that runs the bot and
+// the admin API (api): it validates everything requireBot does, plus apiKey.
+func requireAPI(cmd *cobra.Command, args []string) error {
+	return GetConfig().Validate()
+}
@@ -2,129 +2,30 @@ package cmd
 
 import (
 	"fmt"
-	"git.xx.network/elixxir/coupons/coupons"
-	"git.xx.network/elixxir/coupons/storage"
-	"github.com/skip2/go-qrcode"
 	"github.com/spf13/cobra"
 	jww "github.com/spf13/jwalterweatherman"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	"gitlab.com/elixxir/client/api"
-	"gitlab.com/elixxir/client/interfaces/message"
-	"gitlab.com/elixxir/client/interfaces/params"
-	"gitlab.com/elixxir/crypto/contact"
-	"gitlab.com/xx_network/primitives/id"
 	"gitlab.com/xx_network/primitives/utils"
-	"io/ioutil"
-	"net"
 	"os"
-	"time"
+	"strings"
 )
 
 var (
-	cfgFile, logPath string
-	validConfig      bool
+	cfgFile     string
+	validConfig bool
 )
 
 // RootCmd represents the base command when called without any sub-commands
 var rootCmd = &cobra.Command{
-	Use:   "UDB",
-	Short: "Runs the cMix UDB server.",
-	Long:  "The cMix UDB server handles user and fact registration for the network.",
+	Use:   "coupon-bot",
+	Short: "Runs the cMix coupon bot.",
+	Long:  "The cMix coupon bot issues and redeems coupons over the xx network.",
 	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Initialize config & logging
-		initConfig()
-		initLog()
-
-		// Get database parameters
-		rawAddr := viper.GetString("dbAddress")
-		var addr, port string
-		var err error
-		if rawAddr != "" {
-			addr, port, err = net.SplitHostPort(rawAddr)
-			if err != nil {
-				jww.FATAL.Panicf("Unable to get database port from %s: %+v", rawAddr, err)
-			}
-		}
-
-		sp := storage.Params{
-			Username: viper.GetString("dbUsername"),
-			Password: viper.GetString("dbPassword"),
-			DBName:   viper.GetString("dbName"),
-			Address:  addr,
-			Port:     port,
-		}
-
-		// Initialize storage object
-		s, err := storage.NewStorage(sp)
-		if err != nil {
-			jww.FATAL.Panicf("Failed to initialize storage interface: %+v", err)
-		}
-
-		// Get session parameters
-		sessionPath := viper.GetString("sessionPath")
-		sessionPass := viper.GetString("sessionPass")
-		networkFollowerTimeout := time.Duration(viper.GetInt("networkFollowerTimeout")) * time.Second
-
-		// Create the client if there's no session
-		if _, err := os.Stat(sessionPath); os.IsNotExist(err) {
-			ndfPath := viper.GetString("ndf")
-			ndfJSON, err := ioutil.ReadFile(ndfPath)
-			if err != nil {
-				jww.FATAL.Panicf("Failed to read NDF: %+v", err)
-			}
-			err = api.NewClient(string(ndfJSON), sessionPath, []byte(sessionPass), "")
-			if err != nil {
-				jww.FATAL.Panicf("Failed to create new client: %+v", err)
-			}
-		}
-
-		// Create client object
-		cl, err := api.Login(sessionPath, []byte(sessionPass), params.GetDefaultNetwork())
-		if err != nil {
-			jww.FATAL.Panicf("Failed to initialize client: %+v", err)
-		}
-
-		// Generate QR code
-		qrSize := viper.GetInt("qrSize")
-		qrLevel := qrcode.RecoveryLevel(viper.GetInt("qrLevel"))
-		qrPath := viper.GetString("qrPath")
-		qr, err := cl.GetUser().GetContact().MakeQR(qrSize, qrLevel)
-		if err != nil {
-			jww.FATAL.Panicf("Failed to generate QR code: %+v", err)
-		}
-		// Save the QR code PNG to file
-		err = utils.WriteFile(qrPath, qr, utils.FilePerms, utils.DirPerms)
-		if err != nil {
-			jww.FATAL.Panicf("Failed to write QR code: %+v", err)
-		}
-
-		// Start network follower
-		err = cl.StartNetworkFollower(networkFollowerTimeout)
-		if err != nil {
-			jww.FATAL.Panicf("Failed to start network follower: %+v", err)
-		}
-
-		// Create & register callback to confirm any authenticated channel requests
-		rcb := func(requestor contact.Contact, message string) {
-			rid, err := cl.ConfirmAuthenticatedChannel(requestor)
-			if err != nil {
-				jww.ERROR.Printf("Failed to confirm authentecated channel to %+v: %+v", requestor, err)
-			}
-			jww.DEBUG.Printf("Authenticated channel to %+v created over round %d", requestor, rid)
-		}
-		cl.GetAuthRegistrar().AddGeneralRequestCallback(rcb)
-
-		// Create coupons impl & register listener on zero user for text messages
-		impl := coupons.New(s, cl)
-		cl.GetSwitchboard().RegisterListener(&id.ZeroUser, message.Text, impl)
-
-		// Wait 5ever
-		select {}
-	},
 }
 
-// Execute calls the root command
+// Execute adds all child commands to the root command and runs it. Errors
+// are printed and the process exits non-zero.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		jww.ERROR.Println(err)
@@ -132,15 +33,89 @@ func Execute() {
 	}
 }
 
-// initConfig reads in config file and ENV variables if set.
+func init() {
+	cobra.OnInitialize(func() {
+		initConfig()
+		loadConfig()
+		initLog()
+	})
+
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&cfgFile, "config", "", "Path to the config file (default searches standard locations)")
+
+	flags.String("dbAddress", "", "Database address (host:port)")
+	flags.String("dbUsername", "", "Database username")
+	flags.String("dbPassword", "", "Database password")
+	flags.String("dbName", "", "Database name")
+	flags.String("sessionPath", "coupon-bot-session", "Path to the client session folder")
+	flags.String("sessionPass", "", "Password protecting the client session")
+	flags.String("ndf", "ndf.json", "Path to the network definition file")
+	flags.String("qrPath", "qr.png", "Path to write the registration QR code to")
+	flags.Int("qrSize", 256, "Size, in pixels, of the generated QR code")
+	flags.Int("qrLevel", 0, "Recovery level of the generated QR code")
+	flags.Int("networkFollowerTimeout", 30, "Seconds to wait for the network follower to start")
+	flags.Uint("logLevel", 0, "Verbosity of logging (0 = info, 1 = debug, >1 = trace)")
+	flags.String("logLevelName", "", "Verbosity of logging as a name (trace|debug|info|warn|error), overrides logLevel")
+	flags.String("log", "coupon-bot.log", "Path to the log file")
+	flags.Int("logMaxSize", 100, "Max size, in megabytes, of a log file before it is rotated")
+	flags.Int("logMaxBackups", 3, "Max number of rotated log files to retain")
+	flags.Int("logMaxAge", 28, "Max age, in days, to retain a rotated log file")
+	flags.Bool("logCompress", true, "Compress rotated log files")
+	flags.Bool("logGRPCVerbose", false, "Force verbose gRPC logging regardless of logLevel")
+	flags.Int("apiPort", 8080, "Port the admin/metrics HTTP API listens on")
+	flags.String("apiKey", "", "API key required on the Authorization header of non-health API routes")
+
+	bindPFlags(flags, "dbAddress", "dbUsername", "dbPassword", "dbName",
+		"sessionPath", "sessionPass", "ndf", "qrPath", "qrSize", "qrLevel",
+		"networkFollowerTimeout", "logLevel", "logLevelName", "log",
+		"logMaxSize", "logMaxBackups", "logMaxAge", "logCompress", "logGRPCVerbose",
+		"apiPort", "apiKey")
+
+	viper.SetDefault("qrSize", 256)
+	viper.SetDefault("qrLevel", 0)
+	viper.SetDefault("networkFollowerTimeout", 30)
+	viper.SetDefault("logLevel", 0)
+	viper.SetDefault("sessionPath", "coupon-bot-session")
+	viper.SetDefault("ndf", "ndf.json")
+	viper.SetDefault("qrPath", "qr.png")
+	viper.SetDefault("log", "coupon-bot.log")
+	viper.SetDefault("logMaxSize", 100)
+	viper.SetDefault("logMaxBackups", 3)
+	viper.SetDefault("logMaxAge", 28)
+	viper.SetDefault("logCompress", true)
+	viper.SetDefault("apiPort", 8080)
+
+	viper.SetEnvPrefix("COUPONBOT")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+}
+
+// bindPFlags binds each named persistent flag to its matching viper key.
+func bindPFlags(flags *pflag.FlagSet, names ...string) {
+	for _, name := range names {
+		if err := viper.BindPFlag(name, flags.Lookup(name)); err != nil {
+			jww.FATAL.Panicf("Failed to bind flag %q to viper: %+v", name, err)
+		}
+	}
+}
+
+// configSearchPaths are checked, in order, for a coupon-bot.yaml before
+// falling back to utils.SearchDefaultLocations.
+var configSearchPaths = []string{".", "$HOME/.coupon-bot", "/etc/coupon-bot"}
+
+// initConfig reads in config file and ENV variables if set. A missing config
+// file is not fatal: every subcommand runs through this on OnInitialize
+// (including ones like version that need no config at all), so we fall back
+// to defaults/flags/env rather than refusing to start.
 func initConfig() {
 	validConfig = true
 	var err error
 	if cfgFile == "" {
-		cfgFile, err = utils.SearchDefaultLocations("udb.yaml", "xxnetwork")
+		cfgFile, err = findConfigFile("coupon-bot.yaml")
 		if err != nil {
 			validConfig = false
-			jww.FATAL.Panicf("Failed to find config file: %+v", err)
+			jww.WARN.Printf("No config file found, falling back to defaults/flags/env: %+v", err)
+			return
 		}
 	} else {
 		cfgFile, err = utils.ExpandPath(cfgFile)
@@ -156,46 +131,23 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err != nil {
 		fmt.Printf("Unable to read config file (%s): %+v", cfgFile, err.Error())
 		validConfig = false
+		return
 	}
+	jww.INFO.Printf("Loaded config file %s", cfgFile)
 }
 
-// initLog initializes logging thresholds and the log path.
-func initLog() {
-	vipLogLevel := viper.GetUint("logLevel")
-
-	// Check the level of logs to display
-	if vipLogLevel > 1 {
-		// Set the GRPC log level
-		err := os.Setenv("GRPC_GO_LOG_SEVERITY_LEVEL", "info")
+// findConfigFile looks for name in each of configSearchPaths before falling
+// back to utils.SearchDefaultLocations.
+func findConfigFile(name string) (string, error) {
+	for _, dir := range configSearchPaths {
+		path, err := utils.ExpandPath(dir + "/" + name)
 		if err != nil {
-			jww.ERROR.Printf("Could not set GRPC_GO_LOG_SEVERITY_LEVEL: %+v", err)
+			continue
 		}
-
-		err = os.Setenv("GRPC_GO_LOG_VERBOSITY_LEVEL", "99")
-		if err != nil {
-			jww.ERROR.Printf("Could not set GRPC_GO_LOG_VERBOSITY_LEVEL: %+v", err)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
 		}
-		// Turn on trace logs
-		jww.SetLogThreshold(jww.LevelTrace)
-		jww.SetStdoutThreshold(jww.LevelTrace)
-	} else if vipLogLevel == 1 {
-		// Turn on debugging logs
-		jww.SetLogThreshold(jww.LevelDebug)
-		jww.SetStdoutThreshold(jww.LevelDebug)
-	} else {
-		// Turn on info logs
-		jww.SetLogThreshold(jww.LevelInfo)
-		jww.SetStdoutThreshold(jww.LevelInfo)
 	}
 
-	logPath = viper.GetString("log")
-
-	logFile, err := os.OpenFile(logPath,
-		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
-		0644)
-	if err != nil {
-		fmt.Printf("Could not open log file %s!\n", logPath)
-	} else {
-		jww.SetLogOutput(logFile)
-	}
+	return utils.SearchDefaultLocations(name, "xxnetwork")
 }
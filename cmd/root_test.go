@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindConfigFile_FindsFirstMatchInSearchPaths(t *testing.T) {
+	old := configSearchPaths
+	t.Cleanup(func() { configSearchPaths = old })
+
+	dir := t.TempDir()
+	want := filepath.Join(dir, "coupon-bot.yaml")
+	if err := os.WriteFile(want, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write test config: %+v", err)
+	}
+	later := t.TempDir()
+	if err := os.WriteFile(filepath.Join(later, "coupon-bot.yaml"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write test config: %+v", err)
+	}
+
+	configSearchPaths = []string{filepath.Join(dir, "missing"), dir, later}
+
+	got, err := findConfigFile("coupon-bot.yaml")
+	if err != nil {
+		t.Fatalf("expected to find the config file, got error: %+v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestInitConfig_MissingConfigFileDoesNotPanic(t *testing.T) {
+	oldSearchPaths := configSearchPaths
+	oldCfgFile := cfgFile
+	oldValid := validConfig
+	t.Cleanup(func() {
+		configSearchPaths = oldSearchPaths
+		cfgFile = oldCfgFile
+		validConfig = oldValid
+	})
+
+	// No search path (or the default-locations fallback) should plausibly
+	// contain this, so findConfigFile returns an error and initConfig must
+	// fall back to defaults/flags/env instead of panicking - e.g. so that
+	// `coupon-bot version` still works on a bare checkout.
+	configSearchPaths = nil
+	cfgFile = ""
+
+	initConfig()
+
+	if validConfig {
+		t.Fatal("expected validConfig to be false when no config file is found")
+	}
+}
+
+func TestFindConfigFile_SkipsDirsWithoutTheFile(t *testing.T) {
+	old := configSearchPaths
+	t.Cleanup(func() { configSearchPaths = old })
+
+	empty := t.TempDir()
+	dir := t.TempDir()
+	want := filepath.Join(dir, "coupon-bot.yaml")
+	if err := os.WriteFile(want, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write test config: %+v", err)
+	}
+
+	configSearchPaths = []string{empty, dir}
+
+	got, err := findConfigFile("coupon-bot.yaml")
+	if err != nil {
+		t.Fatalf("expected to find the config file, got error: %+v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
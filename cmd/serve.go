@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// serveCmd starts the client session, generates the registration QR code,
+// and blocks forever while the coupon listener runs on the zero user.
+var serveCmd = &cobra.Command{
+	Use:               "serve",
+	Short:             "Starts the coupon bot and listens for coupon requests.",
+	Long:              "Starts the coupon bot, generating a registration QR code and listening for coupon requests on the zero user until the process is killed.",
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: requireBot,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, _, _, err := startBot()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to start bot: %+v", err)
+		}
+
+		// Wait 5ever
+		select {}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+}
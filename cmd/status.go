@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// statusCmd reports the bot's session and storage health without starting
+// the network follower.
+var statusCmd = &cobra.Command{
+	Use:               "status",
+	Short:             "Reports the bot's session and storage status.",
+	Long:              "Logs in to the bot's existing session and reports its contact ID and storage connectivity.",
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: requireClientAndDB,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := loadStorage()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to initialize storage interface: %+v", err)
+		}
+		defer s.Close()
+
+		cl, err := loadClient()
+		if err != nil {
+			jww.FATAL.Panicf("Failed to initialize client: %+v", err)
+		}
+
+		fmt.Printf("Session user: %s\n", cl.GetUser().GetContact().ID)
+		fmt.Println("Storage: connected")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"git.xx.network/elixxir/coupons/storage"
+	jww "github.com/spf13/jwalterweatherman"
+	"net"
+)
+
+// loadStorage opens the storage backend described by the configured DB
+// settings. It is shared by every subcommand that reads or writes coupon
+// state.
+func loadStorage() (*storage.Storage, error) {
+	db := GetConfig().DB
+
+	var addr, port string
+	var err error
+	if db.Address != "" {
+		addr, port, err = net.SplitHostPort(db.Address)
+		if err != nil {
+			jww.FATAL.Panicf("Unable to get database port from %s: %+v", db.Address, err)
+		}
+	}
+
+	sp := storage.Params{
+		Username: db.Username,
+		Password: db.Password,
+		DBName:   db.Name,
+		Address:  addr,
+		Port:     port,
+	}
+
+	return storage.NewStorage(sp)
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// Version is set at build time via -ldflags.
+var Version = "unknown"
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Prints the coupon bot's version.",
+	Long:  "Prints the coupon bot's version and exits.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("Coupon Bot v%s\n", Version)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}